@@ -2,9 +2,12 @@ package main
 
 import (
 	"concurrency-eval/internal"
+	"concurrency-eval/internal/metrics"
+
 	"github.com/aws/aws-lambda-go/lambda"
 )
 
 func main() {
+	metrics.ServeIfConfigured()
 	lambda.Start(internal.HandleRequest)
 }
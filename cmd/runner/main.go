@@ -2,6 +2,7 @@ package main
 
 import (
 	"concurrency-eval/internal"
+	"concurrency-eval/internal/metrics"
 	"context"
 	"fmt"
 	"log"
@@ -9,6 +10,8 @@ import (
 )
 
 func main() {
+	metrics.ServeIfConfigured()
+
 	event := internal.Event{
 		S3BucketName: os.Getenv("S3_BUCKET_NAME"),
 		Folder:       os.Getenv("FOLDER"),
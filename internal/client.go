@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	smithyendpoints "github.com/aws/smithy-go/endpoints"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+var (
+	s3Once       sync.Once
+	s3DefaultCli *s3v2.Client
+)
+
+// getS3ClientForBucket returns a suitable S3 client. When S3_ENDPOINT_URL is set, it returns a client
+// pointed at that endpoint (MinIO, Ceph RGW, LocalStack, ...), bypassing directory-bucket detection
+// entirely. Otherwise, for S3 Directory Buckets (S3 Express One Zone) it returns a specially configured
+// client that targets the s3express endpoint and injects the required x-amz-region-set header, and for
+// standard buckets it returns a cached default client.
+func getS3ClientForBucket(bucketName string) *s3v2.Client {
+	if endpointURL() != "" {
+		return customEndpointClient()
+	}
+
+	if isDirectoryBucket(bucketName) {
+		return newS3ExpressClient(bucketName)
+	}
+
+	s3Once.Do(func() {
+		cfg, err := config.LoadDefaultConfig(context.Background())
+		if err != nil {
+			panic(fmt.Errorf("failed to load AWS config: %w", err))
+		}
+		s3DefaultCli = s3v2.NewFromConfig(cfg)
+	})
+	return s3DefaultCli
+}
+
+func isDirectoryBucket(bucket string) bool {
+	return strings.HasSuffix(bucket, "--x-s3") && strings.Contains(bucket, "--")
+}
+
+var azIDRe = regexp.MustCompile(`--([a-z0-9-]+)--x-s3$`)
+
+func extractAZID(bucket string) (string, bool) {
+	m := azIDRe.FindStringSubmatch(bucket)
+	if len(m) == 2 {
+		return m[1], true
+	}
+	return "", false
+}
+
+func newS3ExpressClient(bucketName string) *s3v2.Client {
+	azID, ok := extractAZID(bucketName)
+	if !ok {
+		return getS3ClientForBucket("")
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = deriveRegionFromAZID(azID)
+		if region == "" {
+			region = "us-east-1"
+		}
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+	if err != nil {
+		panic(fmt.Errorf("failed to load AWS config for s3express: %w", err))
+	}
+
+	client := s3v2.NewFromConfig(cfg, func(o *s3v2.Options) {
+		// Virtual-hostedâ€“style addressing (required for directory buckets)
+		o.UsePathStyle = false
+		// Inject required header for directory buckets
+		o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+			return stack.Build.Add(middleware.BuildMiddlewareFunc("AddRegionSet", func(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (out middleware.BuildOutput, metadata middleware.Metadata, err error) {
+				if req, ok := in.Request.(*smithyhttp.Request); ok {
+					req.Header.Set("x-amz-region-set", azID)
+				}
+				return next.HandleBuild(ctx, in)
+			}), middleware.After)
+		})
+	})
+
+	return client
+}
+
+func deriveRegionFromAZID(azID string) string {
+	// Minimal mapping for common regions; prefer AWS_REGION env in Lambda.
+	switch {
+	case strings.HasPrefix(azID, "use1-"):
+		return "us-east-1"
+	case strings.HasPrefix(azID, "use2-"):
+		return "us-east-2"
+	case strings.HasPrefix(azID, "usw2-"):
+		return "us-west-2"
+	case strings.HasPrefix(azID, "eun1-"):
+		return "eu-north-1"
+	case strings.HasPrefix(azID, "euw1-"):
+		return "eu-west-1"
+	case strings.HasPrefix(azID, "euc1-"):
+		return "eu-central-1"
+	default:
+		return ""
+	}
+}
+
+// --- Pluggable endpoint support for MinIO, Ceph RGW, LocalStack, and other non-AWS S3-compatible backends ---
+
+func endpointURL() string {
+	return os.Getenv("S3_ENDPOINT_URL")
+}
+
+func disableSSL() bool {
+	return os.Getenv("S3_DISABLE_SSL") == "true"
+}
+
+var (
+	customCliOnce sync.Once
+	customCli     *s3v2.Client
+)
+
+// staticEndpointResolver implements s3v2.EndpointResolverV2, resolving every request to a fixed endpoint
+// URL rather than the AWS-derived s3.<region>.amazonaws.com host.
+type staticEndpointResolver struct {
+	endpoint url.URL
+}
+
+func (r staticEndpointResolver) ResolveEndpoint(_ context.Context, _ s3v2.EndpointParameters) (smithyendpoints.Endpoint, error) {
+	return smithyendpoints.Endpoint{URI: r.endpoint}, nil
+}
+
+func customEndpointClient() *s3v2.Client {
+	customCliOnce.Do(func() {
+		raw := endpointURL()
+		if disableSSL() {
+			raw = strings.Replace(raw, "https://", "http://", 1)
+		}
+		endpoint, err := url.Parse(raw)
+		if err != nil {
+			panic(fmt.Errorf("failed to parse S3_ENDPOINT_URL: %w", err))
+		}
+
+		var opts []func(*config.LoadOptions) error
+		accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+		secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+		if accessKey != "" && secretKey != "" {
+			opts = append(opts, config.WithCredentialsProvider(
+				credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""),
+			))
+		}
+
+		cfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+		if err != nil {
+			panic(fmt.Errorf("failed to load AWS config for custom S3 endpoint: %w", err))
+		}
+
+		customCli = s3v2.NewFromConfig(cfg, func(o *s3v2.Options) {
+			// Custom S3 endpoints (MinIO, Ceph RGW, LocalStack, ...) generally don't support
+			// virtual-hosted-style addressing, so always use path-style here; unlike AWS S3 this isn't
+			// configurable, since the directory-bucket virtual-hosted-style path never applies off AWS.
+			o.UsePathStyle = true
+			o.EndpointResolverV2 = staticEndpointResolver{endpoint: *endpoint}
+		})
+	})
+	return customCli
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"concurrency-eval/internal/metrics"
+)
+
+const (
+	defaultDownloadPartSize    int64 = 8 * 1024 * 1024
+	defaultDownloadConcurrency       = 5
+)
+
+// downloadPartSize returns the byte-range part size used by the s3manager Downloader, defaulting to 8 MiB,
+// overridable via the S3_DOWNLOAD_PART_SIZE env var.
+func downloadPartSize() int64 {
+	v := os.Getenv("S3_DOWNLOAD_PART_SIZE")
+	if v == "" {
+		return defaultDownloadPartSize
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 1 {
+		return defaultDownloadPartSize
+	}
+	return n
+}
+
+// downloadConcurrency returns the number of concurrent byte-range GETs the s3manager Downloader issues per
+// object, defaulting to 5, overridable via the S3_DOWNLOAD_CONCURRENCY env var.
+func downloadConcurrency() int {
+	v := os.Getenv("S3_DOWNLOAD_CONCURRENCY")
+	if v == "" {
+		return defaultDownloadConcurrency
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return defaultDownloadConcurrency
+	}
+	return n
+}
+
+func newDownloader(svc *s3v2.Client) *manager.Downloader {
+	return manager.NewDownloader(svc, func(d *manager.Downloader) {
+		d.PartSize = downloadPartSize()
+		d.Concurrency = downloadConcurrency()
+	})
+}
+
+// get downloads the object at key and either counts it or searches it for *find. Count mode uses the
+// s3manager Downloader to fetch the body via concurrent byte-range GET requests; search mode streams the
+// body in order instead, since it needs to abort the transfer as soon as a match is found (see search.go).
+// Transient failures (throttling, timeouts, network errors) are retried with backoff. It returns the match
+// (if any), the number of bytes read, and how long the operation took, for metrics and Response.Stats.
+func get(ctx context.Context, svc *s3v2.Client, bucketName, key string, find *string) (*string, int64, time.Duration, error) {
+	if find == nil {
+		n, d, err := getCount(ctx, svc, bucketName, key)
+		return nil, n, d, err
+	}
+	return getSearch(ctx, svc, bucketName, key, *find)
+}
+
+// getCount fully downloads the object via range-GETs and discards the result once read.
+func getCount(ctx context.Context, svc *s3v2.Client, bucketName, key string) (int64, time.Duration, error) {
+	downloader := newDownloader(svc)
+	getObjectParams := &s3v2.GetObjectInput{
+		Bucket: awsv2.String(bucketName),
+		Key:    awsv2.String(key),
+	}
+
+	metrics.InflightGets.Inc()
+	defer metrics.InflightGets.Dec()
+
+	start := time.Now()
+	n, err := downloadWithRetry(ctx, downloader, discardAt{}, getObjectParams)
+	duration := time.Since(start)
+
+	metrics.GetDuration.Observe(duration.Seconds())
+	if err != nil {
+		metrics.GetErrorsTotal.WithLabelValues(errorCode(err)).Inc()
+		return n, duration, err
+	}
+	metrics.GetBytesTotal.Add(float64(n))
+	return n, duration, nil
+}
+
+// discardAt is an io.WriterAt that drops every byte it's given, so the s3manager Downloader's concurrent
+// byte-range GETs can run with O(1) memory instead of buffering the whole object (which manager.WriteAtBuffer
+// would do). downloader.Download reports the byte count itself from Content-Length, so discardAt doesn't need
+// to track anything.
+type discardAt struct{}
+
+func (discardAt) WriteAt(p []byte, off int64) (int, error) {
+	return len(p), nil
+}
+
+// downloadWithRetry runs downloader.Download under the default AttemptStrategy, retrying transient errors
+// with exponential backoff and full jitter, bounded by ctx's deadline. It returns the number of bytes
+// written on the last attempt.
+func downloadWithRetry(ctx context.Context, downloader *manager.Downloader, w io.WriterAt, params *s3v2.GetObjectInput) (int64, error) {
+	a := defaultAttemptStrategy().Start()
+	var n int64
+	var err error
+	for a.Next(ctx) {
+		n, err = downloader.Download(ctx, w, params)
+		if err == nil || !isRetryableError(err) {
+			return n, err
+		}
+	}
+	return n, err
+}
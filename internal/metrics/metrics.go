@@ -0,0 +1,59 @@
+// Package metrics exposes Prometheus instrumentation for S3 list/get operations, served on METRICS_ADDR
+// when set.
+package metrics
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	GetDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "s3_get_duration_seconds",
+		Help:    "Duration of per-object S3 GET/download operations.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	GetBytesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "s3_get_bytes_total",
+		Help: "Total bytes read from S3 object bodies.",
+	})
+
+	GetErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "s3_get_errors_total",
+		Help: "Total S3 GET errors, labeled by smithy error code.",
+	}, []string{"code"})
+
+	ListDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "list_duration_seconds",
+		Help:    "Duration of ListObjectsV2 page fetches.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	InflightGets = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "inflight_gets",
+		Help: "Number of S3 GET/download operations currently in flight.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(GetDuration, GetBytesTotal, GetErrorsTotal, ListDuration, InflightGets)
+}
+
+// ServeIfConfigured starts a /metrics endpoint on the address in the METRICS_ADDR env var, in a background
+// goroutine. It is a no-op if METRICS_ADDR is unset.
+func ServeIfConfigured() {
+	addr := os.Getenv("METRICS_ADDR")
+	if addr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		_ = http.ListenAndServe(addr, mux)
+	}()
+}
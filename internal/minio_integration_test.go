@@ -0,0 +1,95 @@
+//go:build integration
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// TestMinIOCountAndFind starts a MinIO container, uploads a handful of objects, and exercises both the
+// count and find code paths against it through S3_ENDPOINT_URL, so the concurrency evaluator can be
+// validated without AWS credentials. Run with `go test -tags integration ./...`; requires Docker.
+func TestMinIOCountAndFind(t *testing.T) {
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "minio/minio:latest",
+		ExposedPorts: []string{"9000/tcp"},
+		Env: map[string]string{
+			"MINIO_ROOT_USER":     "minioadmin",
+			"MINIO_ROOT_PASSWORD": "minioadmin",
+		},
+		Cmd:        []string{"server", "/data"},
+		WaitingFor: wait.ForListeningPort("9000/tcp"),
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start MinIO container: %v", err)
+	}
+	defer func() { _ = container.Terminate(ctx) }()
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get container host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "9000")
+	if err != nil {
+		t.Fatalf("failed to get mapped port: %v", err)
+	}
+
+	t.Setenv("S3_ENDPOINT_URL", fmt.Sprintf("http://%s:%s", host, port.Port()))
+	t.Setenv("AWS_ACCESS_KEY_ID", "minioadmin")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "minioadmin")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	const bucket = "concurrency-eval-test"
+	svc := customEndpointClient()
+	if _, err := svc.CreateBucket(ctx, &s3v2.CreateBucketInput{Bucket: awsv2.String(bucket)}); err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+
+	objects := map[string]string{
+		"a.txt": "hello world",
+		"b.txt": "the needle is here",
+		"c.txt": "nothing to see",
+	}
+	for key, body := range objects {
+		_, err := svc.PutObject(ctx, &s3v2.PutObjectInput{
+			Bucket: awsv2.String(bucket),
+			Key:    awsv2.String(key),
+			Body:   strings.NewReader(body),
+		})
+		if err != nil {
+			t.Fatalf("failed to put object %s: %v", key, err)
+		}
+	}
+
+	countResp, err := HandleRequest(ctx, Event{S3BucketName: bucket})
+	if err != nil {
+		t.Fatalf("count request failed: %v", err)
+	}
+	if countResp.Result == nil || *countResp.Result != strconv.Itoa(len(objects)) {
+		t.Fatalf("expected count %d, got %v", len(objects), countResp.Result)
+	}
+
+	find := "needle"
+	findResp, err := HandleRequest(ctx, Event{S3BucketName: bucket, Find: &find})
+	if err != nil {
+		t.Fatalf("find request failed: %v", err)
+	}
+	if findResp.Result == nil || *findResp.Result != "b.txt" {
+		t.Fatalf("expected match b.txt, got %v", findResp.Result)
+	}
+}
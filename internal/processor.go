@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"concurrency-eval/internal/metrics"
+)
+
+const defaultMaxKeys int32 = 1000
+
+func processor(ctx context.Context, event Event) (*string, *Stats, error) {
+	bucketName := event.S3BucketName
+	svc := getS3ClientForBucket(bucketName)
+	folder := event.Folder
+	find := event.Find
+
+	maxKeys := defaultMaxKeys
+	if event.MaxKeys != nil {
+		maxKeys = *event.MaxKeys
+	}
+
+	// Determine if we're in search mode (find-string provided)
+	searchMode := find != nil
+
+	// In search mode, runCtx is cancelled as soon as the earliest-index match is proven final, so that
+	// in-flight GetObject calls for higher-index keys can abort early instead of fully draining their bodies.
+	runCtx := ctx
+	var cancel context.CancelFunc
+	if searchMode {
+		runCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+	}
+
+	type job struct {
+		idx int
+		key string
+	}
+
+	mc := maxConcurrency()
+	jobs := make(chan job, mc)
+
+	// List objects via the paginator and stream keys into jobs as pages arrive, so downloads for earlier
+	// pages start while later pages are still being listed.
+	var listErr error
+	go func() {
+		defer close(jobs)
+		paginator := s3v2.NewListObjectsV2Paginator(svc, &s3v2.ListObjectsV2Input{
+			Bucket:  awsv2.String(bucketName),
+			Prefix:  awsv2.String(folder),
+			MaxKeys: awsv2.Int32(maxKeys),
+		})
+		idx := 0
+		for paginator.HasMorePages() {
+			listStart := time.Now()
+			page, err := nextPageWithRetry(runCtx, paginator)
+			metrics.ListDuration.Observe(time.Since(listStart).Seconds())
+			if err != nil {
+				if runCtx.Err() == nil {
+					listErr = err
+				}
+				return
+			}
+			for _, obj := range page.Contents {
+				if obj.Key == nil {
+					continue
+				}
+				select {
+				case jobs <- job{idx: idx, key: *obj.Key}:
+					idx++
+				case <-runCtx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	sem := make(chan struct{}, mc)
+	var wg sync.WaitGroup
+
+	// Track first match by original index to satisfy 'first' semantics, plus the set of in-flight indices so
+	// we know when a match can no longer be beaten by an earlier one.
+	var mu sync.Mutex
+	inFlight := make(map[int]struct{})
+	bestIdx := math.MaxInt
+	var bestKey *string
+	total := 0
+	var totalBytes int64
+	var latencies []time.Duration
+
+	// cancelIfFinal must be called with mu held. It cancels runCtx once bestKey is set and no in-flight
+	// goroutine could still produce a lower-index match.
+	cancelIfFinal := func() {
+		if shouldCancelSearch(searchMode, bestKey != nil, bestIdx, inFlight) {
+			cancel()
+		}
+	}
+
+	for j := range jobs {
+		j := j
+		total++
+
+		mu.Lock()
+		inFlight[j.idx] = struct{}{}
+		mu.Unlock()
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer func() {
+				mu.Lock()
+				delete(inFlight, j.idx)
+				cancelIfFinal()
+				mu.Unlock()
+			}()
+
+			match, n, duration, err := get(runCtx, svc, bucketName, j.key, find)
+			if err != nil {
+				if runCtx.Err() != nil {
+					// Aborted because an earlier match was proven final; not a real failure.
+					return
+				}
+				// Log and continue; do not fail entire batch
+				fmt.Println("Error retrieving object:", err)
+				return
+			}
+
+			mu.Lock()
+			totalBytes += n
+			latencies = append(latencies, duration)
+			if searchMode && match != nil && j.idx < bestIdx {
+				bestIdx = j.idx
+				bestKey = match
+			}
+			cancelIfFinal()
+			mu.Unlock()
+		}()
+	}
+
+	// Wait for all reads to complete
+	wg.Wait()
+
+	if listErr != nil {
+		return nil, nil, listErr
+	}
+
+	stats := computeStats(latencies, totalBytes)
+
+	if !searchMode {
+		result := strconv.Itoa(total)
+		return &result, stats, nil
+	}
+
+	// Return the earliest match (may be nil if none found)
+	return bestKey, stats, nil
+}
+
+// shouldCancelSearch reports whether a search-mode match at bestIdx can be proven final: it's true once
+// haveMatch is set and no index still in flight could possibly produce a lower-index (and thus earlier)
+// match than bestIdx.
+func shouldCancelSearch(searchMode, haveMatch bool, bestIdx int, inFlight map[int]struct{}) bool {
+	if !searchMode || !haveMatch {
+		return false
+	}
+	for i := range inFlight {
+		if i < bestIdx {
+			return false
+		}
+	}
+	return true
+}
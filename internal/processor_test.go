@@ -0,0 +1,73 @@
+package internal
+
+import "testing"
+
+func TestShouldCancelSearch(t *testing.T) {
+	tests := []struct {
+		name       string
+		searchMode bool
+		haveMatch  bool
+		bestIdx    int
+		inFlight   map[int]struct{}
+		want       bool
+	}{
+		{
+			name:       "count mode never cancels",
+			searchMode: false,
+			haveMatch:  true,
+			bestIdx:    0,
+			inFlight:   map[int]struct{}{},
+			want:       false,
+		},
+		{
+			name:       "no match yet, nothing to prove final",
+			searchMode: true,
+			haveMatch:  false,
+			bestIdx:    5,
+			inFlight:   map[int]struct{}{0: {}, 1: {}},
+			want:       false,
+		},
+		{
+			name:       "match found, no in-flight work left",
+			searchMode: true,
+			haveMatch:  true,
+			bestIdx:    3,
+			inFlight:   map[int]struct{}{},
+			want:       true,
+		},
+		{
+			name:       "match found, only higher indices still in flight",
+			searchMode: true,
+			haveMatch:  true,
+			bestIdx:    3,
+			inFlight:   map[int]struct{}{4: {}, 7: {}},
+			want:       true,
+		},
+		{
+			name:       "match found, but a lower index is still in flight and could beat it",
+			searchMode: true,
+			haveMatch:  true,
+			bestIdx:    3,
+			inFlight:   map[int]struct{}{1: {}, 4: {}},
+			want:       false,
+		},
+		{
+			name:       "match found, an in-flight index equal to bestIdx does not block cancellation",
+			searchMode: true,
+			haveMatch:  true,
+			bestIdx:    3,
+			inFlight:   map[int]struct{}{3: {}, 5: {}},
+			want:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shouldCancelSearch(tt.searchMode, tt.haveMatch, tt.bestIdx, tt.inFlight)
+			if got != tt.want {
+				t.Fatalf("shouldCancelSearch(%v, %v, %d, %v) = %v, want %v",
+					tt.searchMode, tt.haveMatch, tt.bestIdx, tt.inFlight, got, tt.want)
+			}
+		})
+	}
+}
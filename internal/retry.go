@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// AttemptStrategy configures a bounded retry loop with exponential backoff and full jitter, modeled on the
+// classic goamz aws/attempt.go pattern.
+type AttemptStrategy struct {
+	Total int           // maximum number of attempts, including the first
+	Delay time.Duration // maximum backoff delay between attempts
+	Min   time.Duration // base delay used to compute exponential backoff
+}
+
+// attempt tracks progress through an AttemptStrategy's retry budget.
+type attempt struct {
+	strategy AttemptStrategy
+	count    int
+}
+
+// Start begins a new sequence of retries under the strategy.
+func (s AttemptStrategy) Start() *attempt {
+	return &attempt{strategy: s}
+}
+
+// Next reports whether another attempt should be made. Starting with the second attempt it sleeps for an
+// exponentially increasing, fully-jittered backoff delay before returning, honoring ctx cancellation. It
+// returns false once the attempt budget is exhausted or ctx is done.
+func (a *attempt) Next(ctx context.Context) bool {
+	if a.count >= a.strategy.Total {
+		return false
+	}
+	if a.count > 0 {
+		select {
+		case <-time.After(a.strategy.backoff(a.count)):
+		case <-ctx.Done():
+			return false
+		}
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	a.count++
+	return true
+}
+
+// backoff computes the delay before retry number n (1-based, so n=1 is the wait before the second attempt),
+// as a full-jitter exponential backoff between 0 and min(Min * 2^(n-1), Delay).
+func (s AttemptStrategy) backoff(n int) time.Duration {
+	base := s.Min
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	maxDelay := s.Delay
+	if maxDelay <= 0 {
+		maxDelay = 5 * time.Second
+	}
+
+	shift := n - 1
+	if shift > 20 {
+		shift = 20 // avoid overflowing the int64 shift for pathological attempt counts
+	}
+	d := base << shift
+	if d <= 0 || d > maxDelay {
+		d = maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// defaultAttemptStrategy builds an AttemptStrategy from S3_MAX_ATTEMPTS, S3_RETRY_BASE_DELAY, and
+// S3_RETRY_MAX_DELAY, defaulting to 5 attempts with a 100ms base and 5s cap.
+func defaultAttemptStrategy() AttemptStrategy {
+	return AttemptStrategy{
+		Total: maxAttempts(),
+		Delay: retryMaxDelay(),
+		Min:   retryBaseDelay(),
+	}
+}
+
+func maxAttempts() int {
+	v := os.Getenv("S3_MAX_ATTEMPTS")
+	if v == "" {
+		return 5
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 5
+	}
+	return n
+}
+
+func retryBaseDelay() time.Duration {
+	v := os.Getenv("S3_RETRY_BASE_DELAY")
+	if v == "" {
+		return 100 * time.Millisecond
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 100 * time.Millisecond
+	}
+	return d
+}
+
+func retryMaxDelay() time.Duration {
+	v := os.Getenv("S3_RETRY_MAX_DELAY")
+	if v == "" {
+		return 5 * time.Second
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 5 * time.Second
+	}
+	return d
+}
+
+// isRetryableError reports whether err looks transient: S3 throttling, request timeouts, or a network-level
+// error, as opposed to a permanent failure like AccessDenied or NoSuchKey.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "SlowDown", "RequestTimeout", "ServiceUnavailable", "ThrottlingException", "InternalError":
+			return true
+		default:
+			return false
+		}
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// errorCode classifies err by its smithy API error code, for labeling error metrics. Returns "unknown" for
+// errors that don't carry a smithy error code (e.g. network errors, context cancellation).
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return "unknown"
+}
+
+// getObjectWithRetry runs svc.GetObject under the default AttemptStrategy, retrying transient errors with
+// exponential backoff and full jitter, bounded by ctx's deadline.
+func getObjectWithRetry(ctx context.Context, svc *s3v2.Client, params *s3v2.GetObjectInput) (*s3v2.GetObjectOutput, error) {
+	a := defaultAttemptStrategy().Start()
+	var out *s3v2.GetObjectOutput
+	var err error
+	for a.Next(ctx) {
+		out, err = svc.GetObject(ctx, params)
+		if err == nil || !isRetryableError(err) {
+			return out, err
+		}
+	}
+	return out, err
+}
+
+// nextPageWithRetry fetches the next ListObjectsV2 page under the default AttemptStrategy, retrying
+// transient errors with exponential backoff and full jitter, bounded by ctx's deadline.
+func nextPageWithRetry(ctx context.Context, paginator *s3v2.ListObjectsV2Paginator) (*s3v2.ListObjectsV2Output, error) {
+	a := defaultAttemptStrategy().Start()
+	var page *s3v2.ListObjectsV2Output
+	var err error
+	for a.Next(ctx) {
+		page, err = paginator.NextPage(ctx)
+		if err == nil || !isRetryableError(err) {
+			return page, err
+		}
+	}
+	return page, err
+}
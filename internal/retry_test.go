@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAttemptNext(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategy     AttemptStrategy
+		ctx          func() (context.Context, context.CancelFunc)
+		wantAttempts int
+	}{
+		{
+			name:         "exhausts the attempt budget",
+			strategy:     AttemptStrategy{Total: 3, Min: 0, Delay: 0},
+			ctx:          func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			wantAttempts: 3,
+		},
+		{
+			name:         "single attempt budget",
+			strategy:     AttemptStrategy{Total: 1, Min: 0, Delay: 0},
+			ctx:          func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			wantAttempts: 1,
+		},
+		{
+			name:     "ctx already cancelled before any attempt",
+			strategy: AttemptStrategy{Total: 5, Min: 0, Delay: 0},
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			wantAttempts: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			a := tt.strategy.Start()
+			got := 0
+			for a.Next(ctx) {
+				got++
+			}
+			if got != tt.wantAttempts {
+				t.Fatalf("got %d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+// TestAttemptNextCancelledMidBackoff ensures Next stops waiting as soon as ctx is cancelled, rather than
+// sleeping out the full backoff delay.
+func TestAttemptNextCancelledMidBackoff(t *testing.T) {
+	strategy := AttemptStrategy{Total: 5, Min: time.Hour, Delay: time.Hour}
+	a := strategy.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if !a.Next(ctx) {
+		t.Fatal("expected the first attempt to succeed immediately without backing off")
+	}
+
+	start := time.Now()
+	if a.Next(ctx) {
+		t.Fatal("expected the second attempt to be aborted by ctx cancellation")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Next blocked for %v instead of returning once ctx was cancelled", elapsed)
+	}
+}
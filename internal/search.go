@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"concurrency-eval/internal/metrics"
+)
+
+const searchChunkSize = 64 * 1024
+
+// countingReader wraps an io.Reader and tallies the number of bytes read through it, for byte-count
+// metrics without a separate pass over the data.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// getSearch streams the object at key through GetObject and scans it for find, returning key once a match
+// is located without reading the remainder of the body. It returns the match (if any), the number of bytes
+// read before stopping, and how long the operation took.
+func getSearch(ctx context.Context, svc *s3v2.Client, bucketName, key, find string) (*string, int64, time.Duration, error) {
+	metrics.InflightGets.Inc()
+	defer metrics.InflightGets.Dec()
+
+	start := time.Now()
+	response, err := getObjectWithRetry(ctx, svc, &s3v2.GetObjectInput{
+		Bucket: awsv2.String(bucketName),
+		Key:    awsv2.String(key),
+	})
+	if err != nil {
+		duration := time.Since(start)
+		metrics.GetDuration.Observe(duration.Seconds())
+		metrics.GetErrorsTotal.WithLabelValues(errorCode(err)).Inc()
+		return nil, 0, duration, err
+	}
+	defer response.Body.Close()
+
+	counting := &countingReader{r: response.Body}
+	found, err := searchBody(counting, []byte(find))
+	duration := time.Since(start)
+
+	metrics.GetDuration.Observe(duration.Seconds())
+	if err != nil {
+		metrics.GetErrorsTotal.WithLabelValues(errorCode(err)).Inc()
+		return nil, counting.n, duration, err
+	}
+	metrics.GetBytesTotal.Add(float64(counting.n))
+
+	if found {
+		return &key, counting.n, duration, nil
+	}
+	return nil, counting.n, duration, nil
+}
+
+// searchBody scans r for find using a rolling window: each iteration reads a fixed-size chunk and searches
+// it together with the tail retained from the previous chunk, so matches straddling a chunk boundary are
+// still found. It returns as soon as a match is located, without draining the rest of r.
+func searchBody(r io.Reader, find []byte) (bool, error) {
+	if len(find) == 0 {
+		return true, nil
+	}
+
+	overlap := len(find) - 1
+	buf := make([]byte, searchChunkSize+overlap)
+	carry := 0 // number of valid overlap bytes currently at the front of buf
+
+	for {
+		n, err := r.Read(buf[carry : carry+searchChunkSize])
+		if n > 0 {
+			window := buf[:carry+n]
+			if bytes.Contains(window, find) {
+				return true, nil
+			}
+			if len(window) > overlap {
+				carry = copy(buf, window[len(window)-overlap:])
+			} else {
+				carry = copy(buf, window)
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+}
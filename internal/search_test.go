@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSearchBodyMatchCrossesChunkBoundary(t *testing.T) {
+	find := []byte("needle-in-the-haystack")
+
+	// Place the match straddling the 64 KiB chunk boundary: half of it in the last bytes of the first
+	// chunk, half in the first bytes of the second.
+	straddle := searchChunkSize - len(find)/2
+	var body bytes.Buffer
+	body.WriteString(strings.Repeat("x", straddle))
+	body.Write(find)
+	body.WriteString(strings.Repeat("x", searchChunkSize))
+
+	found, err := searchBody(&body, find)
+	if err != nil {
+		t.Fatalf("searchBody returned error: %v", err)
+	}
+	if !found {
+		t.Fatal("expected searchBody to find the match straddling the chunk boundary")
+	}
+}
+
+func TestSearchBodyNoMatch(t *testing.T) {
+	body := strings.NewReader(strings.Repeat("x", 2*searchChunkSize))
+
+	found, err := searchBody(body, []byte("needle"))
+	if err != nil {
+		t.Fatalf("searchBody returned error: %v", err)
+	}
+	if found {
+		t.Fatal("expected no match")
+	}
+}
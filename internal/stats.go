@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Stats summarizes per-object GET latencies and total bytes transferred for one invocation, turning the
+// previously opaque Response.Time into actionable per-object performance data.
+type Stats struct {
+	MinLatencySeconds float64 `json:"min_latency_seconds"`
+	MaxLatencySeconds float64 `json:"max_latency_seconds"`
+	P50LatencySeconds float64 `json:"p50_latency_seconds"`
+	P95LatencySeconds float64 `json:"p95_latency_seconds"`
+	TotalBytes        int64   `json:"total_bytes"`
+}
+
+// computeStats summarizes latencies and totalBytes into a Stats snapshot. It returns nil if latencies is
+// empty (e.g. no objects were successfully read).
+func computeStats(latencies []time.Duration, totalBytes int64) *Stats {
+	if len(latencies) == 0 {
+		return nil
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &Stats{
+		MinLatencySeconds: sorted[0].Seconds(),
+		MaxLatencySeconds: sorted[len(sorted)-1].Seconds(),
+		P50LatencySeconds: percentile(sorted, 0.50).Seconds(),
+		P95LatencySeconds: percentile(sorted, 0.95).Seconds(),
+		TotalBytes:        totalBytes,
+	}
+}
+
+// percentile returns the p-th percentile (0..1) of sorted (which must already be sorted ascending), using
+// the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}